@@ -0,0 +1,128 @@
+package thumbhash
+
+import (
+	"context"
+	"image"
+	"runtime"
+	"sync"
+)
+
+// BatchOptions contains the parameters used by EncodeImages and
+// EncodeImagesStream. Encoding will use default values for uninitialized
+// members.
+type BatchOptions struct {
+	Concurrency int // the number of worker goroutines (default: runtime.GOMAXPROCS(0))
+}
+
+// EncodeImages computes the hash of each image in imgs concurrently,
+// fanning work out through a bounded pool of goroutines which reuse the
+// same buffer pools as EncodeImage. Hashes are returned in the same order
+// as imgs. If ctx is canceled before every image has been encoded,
+// EncodeImages stops starting new work and returns ctx.Err().
+func EncodeImages(ctx context.Context, imgs []image.Image, opts BatchOptions) ([][]byte, error) {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	hashes := make([][]byte, len(imgs))
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for idx := range indexes {
+				hashes[idx] = EncodeImage(imgs[idx])
+			}
+		}()
+	}
+
+	var err error
+
+dispatch:
+	for i := range imgs {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break dispatch
+		}
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+// IndexedImage associates an image with an opaque index, letting callers
+// of EncodeImagesStream match each IndexedResult back to the image it was
+// computed from.
+type IndexedImage struct {
+	Index int
+	Image image.Image
+}
+
+// IndexedResult is the result of hashing an IndexedImage.
+type IndexedResult struct {
+	Index int
+	Hash  []byte
+	Err   error
+}
+
+// EncodeImagesStream reads images from imgs and writes one IndexedResult
+// per image to results, fanning work out through a bounded pool of
+// opts.Concurrency worker goroutines so that callers processing large
+// media libraries never have to hold every decoded image in memory at
+// once. It closes results once imgs has been drained or ctx is canceled,
+// and returns ctx.Err().
+func EncodeImagesStream(ctx context.Context, imgs <-chan IndexedImage, results chan<- IndexedResult, opts BatchOptions) error {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	defer close(results)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case img, ok := <-imgs:
+					if !ok {
+						return
+					}
+
+					result := IndexedResult{Index: img.Index, Hash: EncodeImage(img.Image)}
+
+					select {
+					case results <- result:
+					case <-ctx.Done():
+						return
+					}
+
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return ctx.Err()
+}
@@ -0,0 +1,134 @@
+package thumbhash
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solidImage(w, h int, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+
+	return img
+}
+
+func TestEncodeImagesConcurrency(t *testing.T) {
+	imgs := make([]image.Image, 64)
+	for i := range imgs {
+		imgs[i] = solidImage(16, 16, color.RGBA{uint8(i * 3), 100, 150, 255})
+	}
+
+	hashes, err := EncodeImages(context.Background(), imgs, BatchOptions{Concurrency: 8})
+	if err != nil {
+		t.Fatalf("cannot encode images: %v", err)
+	}
+
+	for i, img := range imgs {
+		expected := EncodeImage(img)
+		if string(hashes[i]) != string(expected) {
+			t.Errorf("hash %d does not match sequential encoding", i)
+		}
+	}
+}
+
+func TestEncodeImagesCancellation(t *testing.T) {
+	imgs := make([]image.Image, 1000)
+	for i := range imgs {
+		imgs[i] = solidImage(16, 16, color.RGBA{0, 0, 0, 255})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := EncodeImages(ctx, imgs, BatchOptions{Concurrency: 4}); err == nil {
+		t.Fatalf("expected an error from a canceled context")
+	}
+}
+
+// TestEncodeImagesPoolReuse checks that the rgba/lpqa/fx buffer pools
+// shared by EncodeImage are actually being reused across concurrent
+// EncodeImages calls rather than allocated fresh for every image. Each of
+// those buffers is large (the RGBA frame alone is 128x128x4 bytes, the
+// LPQA channels four times that), so failing to reuse them would show up
+// as a sharp jump in allocations per image; successful reuse leaves only
+// the small per-image bookkeeping (hash bytes, AC coefficient slices).
+func TestEncodeImagesPoolReuse(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are unreliable under -race")
+	}
+
+	img := solidImage(64, 64, color.RGBA{50, 100, 150, 255})
+
+	imgs := make([]image.Image, 32)
+	for i := range imgs {
+		imgs[i] = img
+	}
+
+	// Warm up the pools so the steady-state measurement below does not
+	// count their initial population.
+	if _, err := EncodeImages(context.Background(), imgs, BatchOptions{Concurrency: 8}); err != nil {
+		t.Fatalf("cannot warm up pools: %v", err)
+	}
+
+	allocs := testing.AllocsPerRun(20, func() {
+		if _, err := EncodeImages(context.Background(), imgs, BatchOptions{Concurrency: 8}); err != nil {
+			t.Fatalf("cannot encode images: %v", err)
+		}
+	})
+
+	// Without pool reuse, each image would additionally allocate its own
+	// RGBA frame, four LPQA channel slices and an fx slice, on top of the
+	// handful of small allocations already needed per image. The bound
+	// below sits well above observed steady-state reuse but well below
+	// what that extra set of large buffers would add.
+	if maxAllowedAllocs := float64(12 * len(imgs)); allocs > maxAllowedAllocs {
+		t.Errorf("EncodeImages averaged %.1f allocations per run over %d "+
+			"images, which suggests the rgba/lpqa/fx pools are not being "+
+			"reused under contention (expected at most %.1f)",
+			allocs, len(imgs), maxAllowedAllocs)
+	}
+}
+
+func TestEncodeImagesStream(t *testing.T) {
+	imgs := make(chan IndexedImage)
+	results := make(chan IndexedResult)
+
+	go func() {
+		defer close(imgs)
+
+		for i := 0; i < 32; i++ {
+			imgs <- IndexedImage{
+				Index: i,
+				Image: solidImage(16, 16, color.RGBA{uint8(i), 50, 200, 255}),
+			}
+		}
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- EncodeImagesStream(context.Background(), imgs, results, BatchOptions{Concurrency: 4})
+	}()
+
+	seen := make(map[int]bool)
+	for res := range results {
+		if res.Err != nil {
+			t.Fatalf("unexpected error for image %d: %v", res.Index, res.Err)
+		}
+
+		seen[res.Index] = true
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("stream encoding failed: %v", err)
+	}
+
+	if len(seen) != 32 {
+		t.Errorf("got %d results but expected 32", len(seen))
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"go.n16f.net/program"
+	"go.n16f.net/thumbhash"
+)
+
+// fileHash is the result of hashing a single file found while walking a
+// directory with cmdEncodeDir.
+type fileHash struct {
+	Path   string `json:"path"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	Hash   string `json:"hash,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func cmdEncodeDir(p *program.Program) {
+	dirPath := p.ArgumentValue("path")
+	jsonOutput := p.IsOptionSet("json")
+
+	var paths []string
+	err := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		p.Fatal("cannot walk %q: %v", dirPath, err)
+	}
+
+	results := make([]fileHash, len(paths))
+
+	imgs := make(chan thumbhash.IndexedImage)
+	hashes := make(chan thumbhash.IndexedResult)
+
+	go func() {
+		defer close(imgs)
+
+		for i, path := range paths {
+			img, err := readImage(path)
+			if err != nil {
+				results[i] = fileHash{Path: path, Error: err.Error()}
+				continue
+			}
+
+			bounds := img.Bounds()
+			results[i] = fileHash{
+				Path:   path,
+				Width:  bounds.Dx(),
+				Height: bounds.Dy(),
+			}
+
+			imgs <- thumbhash.IndexedImage{Index: i, Image: img}
+		}
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- thumbhash.EncodeImagesStream(context.Background(), imgs, hashes,
+			thumbhash.BatchOptions{})
+	}()
+
+	for res := range hashes {
+		if res.Err != nil {
+			results[res.Index].Error = res.Err.Error()
+			continue
+		}
+
+		results[res.Index].Hash = base64.StdEncoding.EncodeToString(res.Hash)
+	}
+
+	if err := <-done; err != nil {
+		p.Fatal("cannot encode images: %v", err)
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			p.Fatal("cannot marshal json manifest: %v", err)
+		}
+
+		fmt.Println(string(data))
+	} else {
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", r.Path, r.Error)
+				continue
+			}
+
+			fmt.Printf("%s\t%s\n", r.Path, r.Hash)
+		}
+	}
+}
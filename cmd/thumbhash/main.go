@@ -16,6 +16,10 @@ import (
 	"image/draw"
 	_ "image/jpeg"
 	"image/png"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
 )
 
 func main() {
@@ -32,6 +36,13 @@ func main() {
 	c = p.AddCommand("encode-image", "compute the hash of an image file",
 		cmdEncodeImage)
 	c.AddArgument("path", "the path of the image to encode")
+	c.AddFlag("e", "exif-orientation",
+		"honor the EXIF orientation tag of the image if present")
+
+	c = p.AddCommand("encode-dir", "compute the hash of every image in a directory",
+		cmdEncodeDir)
+	c.AddArgument("path", "the path of the directory to walk")
+	c.AddFlag("j", "json", "emit a JSON manifest instead of one line per file")
 
 	c = p.AddCommand("decode-image", "decode an image from a hash",
 		cmdDecodeImage)
@@ -72,12 +83,27 @@ func cmdImageToRawData(p *program.Program) {
 func cmdEncodeImage(p *program.Program) {
 	filePath := p.ArgumentValue("path")
 
-	img, err := readImage(filePath)
-	if err != nil {
-		p.Fatal("cannot read image from %q: %v", filePath, err)
-	}
+	var hash []byte
 
-	hash := thumbhash.EncodeImage(img)
+	if p.IsOptionSet("exif-orientation") {
+		file, err := os.Open(filePath)
+		if err != nil {
+			p.Fatal("cannot open file: %v", err)
+		}
+		defer file.Close()
+
+		hash, err = thumbhash.EncodeImageFromReader(file)
+		if err != nil {
+			p.Fatal("cannot encode image: %v", err)
+		}
+	} else {
+		img, err := readImage(filePath)
+		if err != nil {
+			p.Fatal("cannot read image from %q: %v", filePath, err)
+		}
+
+		hash = thumbhash.EncodeImage(img)
+	}
 
 	fmt.Println(base64.StdEncoding.EncodeToString(hash))
 }
@@ -0,0 +1,60 @@
+package thumbhash
+
+import (
+	"image"
+	_ "image/jpeg"
+	"os"
+	"testing"
+)
+
+func TestDecodeImageToSize(t *testing.T) {
+	file, err := os.Open("data/sunrise.jpg")
+	if err != nil {
+		t.Fatalf("cannot open file: %v", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		t.Fatalf("cannot decode file: %v", err)
+	}
+
+	hash := EncodeImage(img)
+
+	for _, fit := range []Fit{FitContain, FitCover, FitStretch} {
+		out, err := DecodeImageToSize(hash, 300, 100, DecodingCfg{Fit: fit})
+		if err != nil {
+			t.Fatalf("cannot decode to size (fit %d): %v", fit, err)
+		}
+
+		bounds := out.Bounds()
+		if bounds.Dx() != 300 || bounds.Dy() != 100 {
+			t.Errorf("fit %d: image is %dx%d but should be 300x100",
+				fit, bounds.Dx(), bounds.Dy())
+		}
+	}
+}
+
+func TestContainRect(t *testing.T) {
+	src := image.Rect(0, 0, 400, 200) // 2:1 aspect ratio
+
+	got := containRect(src, 300, 300)
+	want := image.Rect(0, 75, 300, 225)
+
+	if got != want {
+		t.Errorf("containRect(%v, 300, 300) is %v but should be %v",
+			src, got, want)
+	}
+}
+
+func TestCoverRect(t *testing.T) {
+	src := image.Rect(0, 0, 400, 200) // 2:1 aspect ratio
+
+	got := coverRect(src, 100, 100)
+	want := image.Rect(100, 0, 300, 200)
+
+	if got != want {
+		t.Errorf("coverRect(%v, 100, 100) is %v but should be %v",
+			src, got, want)
+	}
+}
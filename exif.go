@@ -0,0 +1,109 @@
+package thumbhash
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrInvalidExifData is returned when EXIF metadata is present but
+// malformed.
+var ErrInvalidExifData = errors.New("invalid exif data")
+
+// exifOrientationTagId is the EXIF tag id for the Orientation field.
+const exifOrientationTagId = 0x0112
+
+// exifOrientation scans the bytes of an encoded image (currently only JPEG
+// files carry EXIF data in a form we support) for an APP1 Exif segment and
+// returns the value of its Orientation tag. It returns 1 (the identity
+// orientation) if the image does not contain EXIF data or does not carry an
+// Orientation tag.
+func exifOrientation(data []byte) (int, error) {
+	// JPEG files start with the SOI marker (0xFFD8) followed by a sequence
+	// of segments. We are looking for the APP1 segment (0xFFE1) whose
+	// payload starts with the "Exif\0\0" signature.
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1, nil
+	}
+
+	offset := 2
+
+	for offset+4 <= len(data) {
+		if data[offset] != 0xFF {
+			return 1, nil
+		}
+
+		marker := data[offset+1]
+
+		// SOS marks the start of the entropy-coded image data: there is no
+		// more metadata to look for past this point.
+		if marker == 0xDA {
+			return 1, nil
+		}
+
+		segmentLength := int(binary.BigEndian.Uint16(data[offset+2 : offset+4]))
+		if segmentLength < 2 || offset+2+segmentLength > len(data) {
+			return 1, ErrInvalidExifData
+		}
+
+		payload := data[offset+4 : offset+2+segmentLength]
+
+		if marker == 0xE1 && len(payload) >= 6 && string(payload[:6]) == "Exif\x00\x00" {
+			return exifOrientationFromTIFF(payload[6:])
+		}
+
+		offset += 2 + segmentLength
+	}
+
+	return 1, nil
+}
+
+// exifOrientationFromTIFF reads the Orientation tag out of a TIFF-encoded
+// EXIF block (the part following the "Exif\0\0" signature).
+func exifOrientationFromTIFF(data []byte) (int, error) {
+	if len(data) < 8 {
+		return 1, ErrInvalidExifData
+	}
+
+	var order binary.ByteOrder
+	switch string(data[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1, ErrInvalidExifData
+	}
+
+	if order.Uint16(data[2:4]) != 42 {
+		return 1, ErrInvalidExifData
+	}
+
+	ifdOffset := int(order.Uint32(data[4:8]))
+	if ifdOffset+2 > len(data) {
+		return 1, ErrInvalidExifData
+	}
+
+	nbEntries := int(order.Uint16(data[ifdOffset : ifdOffset+2]))
+	entriesStart := ifdOffset + 2
+
+	for i := 0; i < nbEntries; i++ {
+		entryOffset := entriesStart + i*12
+		if entryOffset+12 > len(data) {
+			return 1, ErrInvalidExifData
+		}
+
+		tagId := order.Uint16(data[entryOffset : entryOffset+2])
+		if tagId != exifOrientationTagId {
+			continue
+		}
+
+		value := order.Uint16(data[entryOffset+8 : entryOffset+10])
+		if value < 1 || value > 8 {
+			return 1, nil
+		}
+
+		return int(value), nil
+	}
+
+	return 1, nil
+}
@@ -0,0 +1,196 @@
+package thumbhash
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func TestExifOrientation(t *testing.T) {
+	buildJPEG := func(order string, orientation uint16) []byte {
+		var tiff []byte
+		put16 := func(v uint16) {
+			if order == "II" {
+				tiff = append(tiff, byte(v), byte(v>>8))
+			} else {
+				tiff = append(tiff, byte(v>>8), byte(v))
+			}
+		}
+		put32 := func(v uint32) {
+			if order == "II" {
+				tiff = append(tiff, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+			} else {
+				tiff = append(tiff, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+			}
+		}
+
+		tiff = append(tiff, order[0], order[1])
+		put16(42)
+		put32(8) // IFD0 offset
+		put16(1) // one entry
+		put16(exifOrientationTagId)
+		put16(3) // type SHORT
+		put32(1) // count
+		put16(orientation)
+		tiff = append(tiff, 0, 0) // value padded to 4 bytes
+
+		app1 := append([]byte("Exif\x00\x00"), tiff...)
+
+		segmentLength := len(app1) + 2
+		jpeg := []byte{0xFF, 0xD8, 0xFF, 0xE1, byte(segmentLength >> 8), byte(segmentLength)}
+		jpeg = append(jpeg, app1...)
+		jpeg = append(jpeg, 0xFF, 0xDA) // start of scan, no more metadata
+
+		return jpeg
+	}
+
+	for _, order := range []string{"II", "MM"} {
+		for orientation := 1; orientation <= 8; orientation++ {
+			data := buildJPEG(order, uint16(orientation))
+
+			got, err := exifOrientation(data)
+			if err != nil {
+				t.Fatalf("cannot read orientation (%s, %d): %v",
+					order, orientation, err)
+			}
+
+			if got != orientation {
+				t.Errorf("orientation for (%s, %d) is %d but should be %d",
+					order, orientation, got, orientation)
+			}
+		}
+	}
+
+	// No EXIF data at all: default to the identity orientation.
+	plainJPEG := []byte{0xFF, 0xD8, 0xFF, 0xDA}
+	got, err := exifOrientation(plainJPEG)
+	if err != nil {
+		t.Fatalf("cannot read orientation of plain JPEG: %v", err)
+	}
+	if got != 1 {
+		t.Errorf("orientation of plain JPEG is %d but should be 1", got)
+	}
+}
+
+func TestExifOrientationMalformed(t *testing.T) {
+	// An APP1 Exif segment whose payload is too short to contain a TIFF
+	// header: the kind of truncated metadata some camera/app pipelines do
+	// produce.
+	app1 := append([]byte("Exif\x00\x00"), 'I', 'I')
+	segmentLength := len(app1) + 2
+
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1, byte(segmentLength >> 8), byte(segmentLength)}
+	data = append(data, app1...)
+	data = append(data, 0xFF, 0xDA)
+
+	if _, err := exifOrientation(data); err != ErrInvalidExifData {
+		t.Errorf("exifOrientation returned %v but should have returned %v",
+			err, ErrInvalidExifData)
+	}
+}
+
+func TestApplyExifOrientation(t *testing.T) {
+	// A 2x3 image where every pixel carries a distinct value, so that any
+	// axis swap, flip or off-by-one in applyExifOrientation shows up as a
+	// pixel landing in the wrong place rather than cancelling out.
+	const w, h = 2, 3
+	values := [h][w]uint8{
+		{10, 20},
+		{30, 40},
+		{50, 60},
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			src.Set(x, y, color.RGBA{values[y][x], 0, 0, 255})
+		}
+	}
+
+	valueAt := func(img image.Image, x, y int) uint8 {
+		r, _, _, _ := img.At(x, y).RGBA()
+		return uint8(r >> 8)
+	}
+
+	// Expected layouts hand-derived from the rotation/flip performed for
+	// each orientation, indexed [y][x] in the destination image.
+	expected := map[int][][]uint8{
+		1: {{10, 20}, {30, 40}, {50, 60}}, // identity
+		2: {{20, 10}, {40, 30}, {60, 50}}, // flip horizontal
+		3: {{60, 50}, {40, 30}, {20, 10}}, // rotate 180
+		4: {{50, 60}, {30, 40}, {10, 20}}, // flip vertical
+		5: {{10, 30, 50}, {20, 40, 60}},   // transpose
+		6: {{50, 30, 10}, {60, 40, 20}},   // rotate 90 CW
+		7: {{60, 40, 20}, {50, 30, 10}},   // transverse
+		8: {{20, 40, 60}, {10, 30, 50}},   // rotate 270 CW
+	}
+
+	for orientation := 1; orientation <= 8; orientation++ {
+		dst := applyExifOrientation(src, orientation)
+		want := expected[orientation]
+
+		bounds := dst.Bounds()
+		if bounds.Dy() != len(want) || bounds.Dx() != len(want[0]) {
+			t.Fatalf("orientation %d: image is %dx%d but should be %dx%d",
+				orientation, bounds.Dx(), bounds.Dy(), len(want[0]), len(want))
+		}
+
+		for y := 0; y < bounds.Dy(); y++ {
+			for x := 0; x < bounds.Dx(); x++ {
+				got := valueAt(dst, x, y)
+				if got != want[y][x] {
+					t.Errorf("orientation %d: pixel (%d, %d) is %d but should be %d",
+						orientation, x, y, got, want[y][x])
+				}
+			}
+		}
+	}
+}
+
+func buildJPEGWithMalformedExif(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("cannot encode jpeg fixture: %v", err)
+	}
+
+	realJPEG := buf.Bytes()
+	if realJPEG[0] != 0xFF || realJPEG[1] != 0xD8 {
+		t.Fatalf("jpeg fixture does not start with a SOI marker")
+	}
+
+	app1 := append([]byte("Exif\x00\x00"), 'I', 'I')
+	segmentLength := len(app1) + 2
+
+	malformed := append([]byte{}, realJPEG[:2]...)
+	malformed = append(malformed, 0xFF, 0xE1,
+		byte(segmentLength>>8), byte(segmentLength))
+	malformed = append(malformed, app1...)
+	malformed = append(malformed, realJPEG[2:]...)
+
+	return malformed
+}
+
+func TestEncodeImageFromReaderMalformedExif(t *testing.T) {
+	data := buildJPEGWithMalformedExif(t)
+
+	hash, err := EncodeImageFromReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("EncodeImageFromReader failed on a malformed (but "+
+			"otherwise decodable) image: %v", err)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("cannot decode fixture: %v", err)
+	}
+
+	expected := EncodeImage(img)
+	if string(hash) != string(expected) {
+		t.Errorf("hash with a malformed EXIF block does not match the " +
+			"hash of the same image decoded directly")
+	}
+}
@@ -0,0 +1,72 @@
+package thumbhash
+
+import (
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"testing"
+)
+
+func TestHashDistance(t *testing.T) {
+	decodeHash := func(filePath string) *Hash {
+		file, err := os.Open(filePath)
+		if err != nil {
+			t.Fatalf("cannot open %q: %v", filePath, err)
+		}
+		defer file.Close()
+
+		img, _, err := image.Decode(file)
+		if err != nil {
+			t.Fatalf("cannot decode %q: %v", filePath, err)
+		}
+
+		var hash Hash
+		cfg := DecodingCfg{SaturationBoost: 1.25}
+		if err := hash.Decode(EncodeImage(img), &cfg); err != nil {
+			t.Fatalf("cannot decode hash of %q: %v", filePath, err)
+		}
+
+		return &hash
+	}
+
+	sunrise := decodeHash("data/sunrise.jpg")
+	largeSunrise := decodeHash("data/large-sunrise.png")
+	firefox := decodeHash("data/firefox.png")
+
+	sameImageDistance := sunrise.Distance(largeSunrise)
+	differentImageDistance := sunrise.Distance(firefox)
+
+	if sameImageDistance >= differentImageDistance {
+		t.Errorf("distance between two sizes of the same photo (%f) should "+
+			"be smaller than the distance to an unrelated image (%f)",
+			sameImageDistance, differentImageDistance)
+	}
+
+	if d := sunrise.Distance(sunrise); d != 0 {
+		t.Errorf("distance between a hash and itself is %f but should be 0", d)
+	}
+}
+
+func TestSimilar(t *testing.T) {
+	file, err := os.Open("data/sunrise.jpg")
+	if err != nil {
+		t.Fatalf("cannot open file: %v", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		t.Fatalf("cannot decode file: %v", err)
+	}
+
+	hash := EncodeImage(img)
+
+	similar, err := Similar(hash, hash, 0.4)
+	if err != nil {
+		t.Fatalf("cannot compare hashes: %v", err)
+	}
+	if !similar {
+		t.Errorf("a hash should be similar to itself")
+	}
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package thumbhash
+
+const raceEnabled = false
@@ -0,0 +1,9 @@
+//go:build race
+
+package thumbhash
+
+// raceEnabled reports whether the test binary was built with -race. The
+// race detector's own instrumentation perturbs allocation counts, so
+// allocation-counting tests such as TestEncodeImagesPoolReuse skip
+// themselves under it rather than chase a moving target.
+const raceEnabled = true
@@ -1,8 +1,11 @@
 package thumbhash
 
 import (
+	"bytes"
+	"fmt"
 	"image"
 	"image/draw"
+	"io"
 	"math"
 	"sync"
 
@@ -51,12 +54,55 @@ type lpqaBuf struct {
 // DecodingCfg contains the parameters used for image decoding. Decoding will
 // use default values for uninitialized members.
 type DecodingCfg struct {
-	BaseSize        int     // the base image size (default: 32px)
-	SaturationBoost float64 // the factor applied to increase image saturation (default: 1.25)
+	BaseSize        int                // the base image size (default: 32px)
+	SaturationBoost float64            // the factor applied to increase image saturation (default: 1.25)
+	Upscaler        xdraw.Interpolator // the filter used by DecodeImageToSize (default: xdraw.CatmullRom)
+	Fit             Fit                // how DecodeImageToSize fits the image into the target size (default: FitContain)
 }
 
-// EncodeImage returns the binary hash of an image.
+// Fit determines how DecodeImageToSize fits the decoded image into a
+// target size whose aspect ratio may differ from that of the image.
+type Fit int
+
+const (
+	// FitContain scales the image down to fit entirely within the target
+	// size, letterboxing the remaining space with transparent pixels.
+	FitContain Fit = iota
+
+	// FitCover scales the image up to cover the target size entirely,
+	// cropping whatever overflows.
+	FitCover
+
+	// FitStretch scales the image to the target size directly, ignoring
+	// its aspect ratio.
+	FitStretch
+)
+
+// EncodingCfg contains the parameters used for image encoding. Encoding
+// will use default values for uninitialized members.
+type EncodingCfg struct {
+	Resampler xdraw.Interpolator // the filter used to downscale large images (default: xdraw.CatmullRom)
+	MaxDim    int                // the maximum encoding dimension in pixels (default: 128px)
+}
+
+// EncodeImage returns the binary hash of an image using the default
+// encoding configuration.
 func EncodeImage(img image.Image) []byte {
+	return EncodeImageWithCfg(img, EncodingCfg{})
+}
+
+// EncodeImageWithCfg returns the binary hash of an image.
+func EncodeImageWithCfg(img image.Image, cfg EncodingCfg) []byte {
+	// Configuration default values
+	if cfg.Resampler == nil {
+		cfg.Resampler = xdraw.CatmullRom
+	}
+
+	maxDim := cfg.MaxDim
+	if maxDim <= 0 || maxDim > maxEncodeDim {
+		maxDim = maxEncodeDim
+	}
+
 	bounds := img.Bounds()
 	w, h := bounds.Dx(), bounds.Dy()
 
@@ -66,17 +112,17 @@ func EncodeImage(img image.Image) []byte {
 
 	// resize images larger than max encoding dimension
 	// (no point in encoding large images)
-	if maxDim := max(w, h); maxDim > maxEncodeDim {
+	if imgMaxDim := max(w, h); imgMaxDim > maxDim {
 		var scaleFactor float64
 		if w > h {
-			scaleFactor = maxEncodeDim / float64(w)
+			scaleFactor = float64(maxDim) / float64(w)
 		} else {
-			scaleFactor = maxEncodeDim / float64(h)
+			scaleFactor = float64(maxDim) / float64(h)
 		}
 
 		w = int(float64(w) * scaleFactor)
 		h = int(float64(h) * scaleFactor)
-		xdraw.NearestNeighbor.Scale(rgba, image.Rect(0, 0, w, h), img, bounds, draw.Src, nil)
+		cfg.Resampler.Scale(rgba, image.Rect(0, 0, w, h), img, bounds, draw.Src, nil)
 	} else {
 		draw.Draw(rgba, image.Rect(0, 0, w, h), img, bounds.Min, draw.Src)
 	}
@@ -220,6 +266,81 @@ func EncodeImage(img image.Image) []byte {
 	return hash.Encode()
 }
 
+// EncodeImageFromReader reads the encoded bytes of an image, honors its
+// EXIF Orientation tag when present (rotating and/or flipping the decoded
+// pixels so that the image is stored upright before hashing), and returns
+// the resulting hash. Callers that already work with normalized images,
+// or whose images do not carry EXIF data, can use EncodeImage directly.
+func EncodeImageFromReader(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read image data: %w", err)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode image: %w", err)
+	}
+
+	// A malformed EXIF block (real-world camera and app output does produce
+	// these) is treated the same as the absence of one: the image still
+	// decoded fine, so we hash it as-is instead of failing the whole
+	// encode over orientation metadata.
+	orientation, err := exifOrientation(data)
+	if err != nil {
+		orientation = 1
+	}
+
+	if orientation > 1 {
+		img = applyExifOrientation(img, orientation)
+	}
+
+	return EncodeImage(img), nil
+}
+
+// applyExifOrientation returns a copy of img rotated and/or flipped
+// according to one of the 8 EXIF Orientation values.
+func applyExifOrientation(img image.Image, orientation int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	var dst *image.RGBA
+	if orientation >= 5 {
+		dst = image.NewRGBA(image.Rect(0, 0, h, w))
+	} else {
+		dst = image.NewRGBA(image.Rect(0, 0, w, h))
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+
+			switch orientation {
+			case 2: // flip horizontal
+				dx, dy = w-1-x, y
+			case 3: // rotate 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // flip vertical
+				dx, dy = x, h-1-y
+			case 5: // transpose
+				dx, dy = y, x
+			case 6: // rotate 90 CW
+				dx, dy = h-1-y, x
+			case 7: // transverse
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotate 270 CW
+				dx, dy = y, w-1-x
+			default:
+				dx, dy = x, y
+			}
+
+			dst.Set(dx, dy, img.At(bounds.Min.X+x, bounds.Min.Y+y))
+		}
+	}
+
+	return dst
+}
+
 // DecodeImage returns the image associated with a binary hash using the
 // default decoding configuration.
 func DecodeImage(hashData []byte) (image.Image, error) {
@@ -331,6 +452,77 @@ func DecodeImageWithCfg(hashData []byte, cfg DecodingCfg) (image.Image, error) {
 	return img, nil
 }
 
+// DecodeImageToSize decodes a hash at its natural small size and resamples
+// the result to the w x h target size using cfg.Upscaler, fitting it
+// according to cfg.Fit.
+func DecodeImageToSize(hashData []byte, w, h int, cfg DecodingCfg) (image.Image, error) {
+	// Configuration default values
+	if cfg.Upscaler == nil {
+		cfg.Upscaler = xdraw.CatmullRom
+	}
+
+	img, err := DecodeImageWithCfg(hashData, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	switch cfg.Fit {
+	case FitCover:
+		srcRect := coverRect(img.Bounds(), w, h)
+		cfg.Upscaler.Scale(dst, dst.Bounds(), img, srcRect, draw.Src, nil)
+
+	case FitStretch:
+		cfg.Upscaler.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	default: // FitContain
+		dstRect := containRect(img.Bounds(), w, h)
+		cfg.Upscaler.Scale(dst, dstRect, img, img.Bounds(), draw.Src, nil)
+	}
+
+	return dst, nil
+}
+
+// containRect returns the largest rectangle with the same aspect ratio as
+// srcBounds that fits within a w x h area, centered within it.
+func containRect(srcBounds image.Rectangle, w, h int) image.Rectangle {
+	srcW, srcH := srcBounds.Dx(), srcBounds.Dy()
+
+	scale := math.Min(float64(w)/float64(srcW), float64(h)/float64(srcH))
+
+	dw := iround(float64(srcW) * scale)
+	dh := iround(float64(srcH) * scale)
+
+	x0 := (w - dw) / 2
+	y0 := (h - dh) / 2
+
+	return image.Rect(x0, y0, x0+dw, y0+dh)
+}
+
+// coverRect returns the centered sub-rectangle of srcBounds which, once
+// scaled to a w x h area, covers it entirely.
+func coverRect(srcBounds image.Rectangle, w, h int) image.Rectangle {
+	srcW, srcH := srcBounds.Dx(), srcBounds.Dy()
+
+	targetRatio := float64(w) / float64(h)
+	srcRatio := float64(srcW) / float64(srcH)
+
+	var cropW, cropH int
+	if srcRatio > targetRatio {
+		cropH = srcH
+		cropW = iround(float64(srcH) * targetRatio)
+	} else {
+		cropW = srcW
+		cropH = iround(float64(srcW) / targetRatio)
+	}
+
+	x0 := srcBounds.Min.X + (srcW-cropW)/2
+	y0 := srcBounds.Min.Y + (srcH-cropH)/2
+
+	return image.Rect(x0, y0, x0+cropW, y0+cropH)
+}
+
 func iround(x float64) int {
 	return int(math.Round(x))
 }
@@ -3,10 +3,14 @@ package thumbhash
 import (
 	"encoding/base64"
 	"image"
+	"image/draw"
 	_ "image/jpeg"
 	_ "image/png"
+	"math"
 	"os"
 	"testing"
+
+	xdraw "golang.org/x/image/draw"
 )
 
 func TestEncodeImage(t *testing.T) {
@@ -35,3 +39,96 @@ func TestEncodeImage(t *testing.T) {
 	checkImage("X5qGNQw7oElslqhGWfSE+Q6oJ1h2iHB2Rw==", "data/firefox.png")
 	checkImage("VvYRNQRod313B4h3eHhYiHeAiQUo", "data/large-sunrise.png")
 }
+
+// BenchmarkEncodeImageResamplers times EncodeImageWithCfg under each
+// downscale filter available through EncodingCfg.Resampler, and reports,
+// via b.ReportMetric, the standard deviation of the resulting L channel
+// AC coefficients across a handful of 1-2px shifted crops of the same
+// source image. A resampler that aliases less should bake less of that
+// irrelevant pixel-level jitter into the hash, and so report a lower
+// coefficient-stddev metric alongside its timing.
+func BenchmarkEncodeImageResamplers(b *testing.B) {
+	file, err := os.Open("data/large-sunrise.png")
+	if err != nil {
+		b.Fatalf("cannot open file: %v", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		b.Fatalf("cannot decode file: %v", err)
+	}
+
+	bounds := img.Bounds()
+	shifts := []image.Point{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {2, 1}}
+
+	resamplers := []struct {
+		name      string
+		resampler xdraw.Interpolator
+	}{
+		{"NearestNeighbor", xdraw.NearestNeighbor},
+		{"CatmullRom", xdraw.CatmullRom},
+	}
+
+	for _, r := range resamplers {
+		cfg := EncodingCfg{Resampler: r.resampler}
+
+		b.Run(r.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				EncodeImageWithCfg(img, cfg)
+			}
+
+			b.StopTimer()
+			b.ReportMetric(lacStability(b, img, bounds, shifts, cfg), "LAC-stddev")
+		})
+	}
+}
+
+// lacStability encodes a shifted-crop variant of img for each point in
+// shifts, decodes the resulting hash, and returns the standard deviation
+// of each L channel AC coefficient across those variants, averaged over
+// all coefficients. Every crop has the same, fully opaque dimensions: the
+// base crop rectangle is inset far enough from img's edges that no shift
+// in shifts can read past them, so hasAlpha (and therefore the number of
+// AC coefficients) stays the same across all variants.
+func lacStability(b *testing.B, img image.Image, bounds image.Rectangle, shifts []image.Point, cfg EncodingCfg) float64 {
+	cropBounds := image.Rect(
+		bounds.Min.X, bounds.Min.Y, bounds.Max.X-2, bounds.Max.Y-2)
+	dstBounds := image.Rect(0, 0, cropBounds.Dx(), cropBounds.Dy())
+
+	var lacs [][]float64
+
+	for _, shift := range shifts {
+		shifted := image.NewRGBA(dstBounds)
+		draw.Draw(shifted, dstBounds, img, cropBounds.Min.Add(shift), draw.Src)
+
+		hash := EncodeImageWithCfg(shifted, cfg)
+
+		var h Hash
+		if err := h.Decode(hash, &DecodingCfg{SaturationBoost: 1.25}); err != nil {
+			b.Fatalf("cannot decode hash: %v", err)
+		}
+
+		lacs = append(lacs, h.LAC)
+	}
+
+	n := len(lacs[0])
+	var sumVariance float64
+
+	for i := 0; i < n; i++ {
+		var mean float64
+		for _, lac := range lacs {
+			mean += lac[i]
+		}
+		mean /= float64(len(lacs))
+
+		var variance float64
+		for _, lac := range lacs {
+			d := lac[i] - mean
+			variance += d * d
+		}
+		sumVariance += variance / float64(len(lacs))
+	}
+
+	return math.Sqrt(sumVariance / float64(n))
+}